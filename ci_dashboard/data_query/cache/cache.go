@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides a persistent, on-disk cache of GitHub API responses
+// keyed by owner/repo/workflow or owner/repo/run, so repeated collector runs
+// can issue conditional requests (If-None-Match / If-Modified-Since) instead
+// of re-fetching data that hasn't changed.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Entry is the cached copy of a single API response.
+type Entry struct {
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	BodyDigest   string            `json:"body_digest,omitempty"`
+	Body         []byte            `json:"body"`
+	Header       map[string]string `json:"header,omitempty"`
+	StatusCode   int               `json:"status_code"`
+	StoredAt     time.Time         `json:"stored_at"`
+}
+
+// Store is a disk-backed collection of Entry values keyed by an arbitrary
+// string (see RepoWorkflowKey and RepoRunKey). It is safe for concurrent use.
+type Store struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// NewStore loads a Store from path, creating an empty one if the file
+// doesn't exist yet. path is a single JSON file, not a directory.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]*Entry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns the cached entry for key, if any.
+func (s *Store) Get(key string) (*Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+// Put stores (or replaces) the entry for key. It does not persist to disk;
+// call Save once the collector run is finished.
+func (s *Store) Put(key string, entry *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry
+}
+
+// Save persists the store to disk, creating parent directories as needed.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// Digest returns a hex-encoded SHA-256 digest of body, stored alongside the
+// cached entry so callers can detect a body change even when a server
+// mistakenly omits ETag/Last-Modified.
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// RepoWorkflowKey builds the cache key for a (owner, repo, workflowID)
+// triple, e.g. the "list runs for workflow" endpoint.
+func RepoWorkflowKey(owner, repo string, workflowID int64) string {
+	return "workflow:" + owner + "/" + repo + "/" + strconv.FormatInt(workflowID, 10)
+}
+
+// RepoRunKey builds the cache key for a (owner, repo, runID) triple, e.g.
+// the "list jobs for run" endpoint.
+func RepoRunKey(owner, repo string, runID int64) string {
+	return "run:" + owner + "/" + repo + "/" + strconv.FormatInt(runID, 10)
+}
+
+// RepoKey builds the cache key for a (owner, repo) pair, e.g. the "list
+// workflows" endpoint.
+func RepoKey(owner, repo string) string {
+	return "repo:" + owner + "/" + repo
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// KeyFunc derives a Store key for an outgoing request. It returns ok=false
+// for requests that shouldn't be cached (key is ignored in that case).
+type KeyFunc func(req *http.Request) (key string, ok bool)
+
+// Transport wraps another http.RoundTripper, adding conditional-request
+// headers for requests KeyFunc recognizes and answering a 304 with the body
+// from Store as a 200, so callers get the decoded body they expect.
+type Transport struct {
+	Base    http.RoundTripper
+	Store   *Store
+	KeyFunc KeyFunc
+
+	Hits   int64
+	Misses int64
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		key    string
+		cached bool
+		entry  *Entry
+	)
+	if t.KeyFunc != nil {
+		if k, ok := t.KeyFunc(req); ok {
+			key = k
+			entry, cached = t.Store.Get(key)
+			if cached {
+				if entry.ETag != "" {
+					req.Header.Set("If-None-Match", entry.ETag)
+				}
+				if entry.LastModified != "" {
+					req.Header.Set("If-Modified-Since", entry.LastModified)
+				}
+			}
+		}
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		atomic.AddInt64(&t.Hits, 1)
+		resp.Body.Close()
+		return entry.response(req), nil
+	}
+
+	if key != "" && resp.StatusCode == http.StatusOK {
+		atomic.AddInt64(&t.Misses, 1)
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr == nil {
+			header := make(map[string]string, len(resp.Header))
+			for k := range resp.Header {
+				header[k] = resp.Header.Get(k)
+			}
+			t.Store.Put(key, &Entry{
+				ETag:         resp.Header.Get("ETag"),
+				LastModified: resp.Header.Get("Last-Modified"),
+				BodyDigest:   Digest(body),
+				Body:         body,
+				Header:       header,
+				StatusCode:   resp.StatusCode,
+				StoredAt:     time.Now(),
+			})
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+		}
+	}
+
+	return resp, nil
+}
+
+// response reconstructs a 200 OK *http.Response from a cached Entry, so a
+// 304 from the server is transparent to callers expecting a decoded body.
+func (e *Entry) response(req *http.Request) *http.Response {
+	header := make(http.Header, len(e.Header))
+	for k, v := range e.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
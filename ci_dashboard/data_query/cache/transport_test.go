@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeRoundTripper is an in-memory http.RoundTripper double that returns one
+// canned response per call, in order, and records the requests it saw.
+type fakeRoundTripper struct {
+	responses []*http.Response
+	requests  []*http.Request
+}
+
+func (f *fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := f.responses[len(f.requests)]
+	f.requests = append(f.requests, req)
+	resp.Request = req
+	return resp, nil
+}
+
+func newReq(t *testing.T) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/o/r/actions/workflows/1/runs", nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	return req
+}
+
+func alwaysCache(req *http.Request) (string, bool) { return "k", true }
+
+// TestTransportCachesOnFirstRequest checks that a 200 response with an ETag
+// is stored and counted as a miss.
+func TestTransportCachesOnFirstRequest(t *testing.T) {
+	base := &fakeRoundTripper{responses: []*http.Response{{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": []string{`"v1"`}},
+		Body:       io.NopCloser(strings.NewReader(`{"hello":"world"}`)),
+	}}}
+	transport := &Transport{Base: base, Store: &Store{entries: map[string]*Entry{}}, KeyFunc: alwaysCache}
+
+	resp, err := transport.RoundTrip(newReq(t))
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"hello":"world"}` {
+		t.Errorf("body = %q, want the upstream body unchanged", body)
+	}
+	if transport.Misses != 1 || transport.Hits != 0 {
+		t.Errorf("Hits=%d Misses=%d, want Hits=0 Misses=1", transport.Hits, transport.Misses)
+	}
+	if _, ok := transport.Store.Get("k"); !ok {
+		t.Errorf("expected the response to be stored under key %q", "k")
+	}
+}
+
+// TestTransportServesCachedBodyOn304 checks that a second request reuses
+// the stored ETag, and a 304 response is served from the cache instead of
+// the (empty) 304 body, counted as a hit.
+func TestTransportServesCachedBodyOn304(t *testing.T) {
+	base := &fakeRoundTripper{responses: []*http.Response{
+		{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Etag": []string{`"v1"`}},
+			Body:       io.NopCloser(strings.NewReader(`{"hello":"world"}`)),
+		},
+		{
+			StatusCode: http.StatusNotModified,
+			Body:       io.NopCloser(strings.NewReader("")),
+		},
+	}}
+	transport := &Transport{Base: base, Store: &Store{entries: map[string]*Entry{}}, KeyFunc: alwaysCache}
+
+	if _, err := transport.RoundTrip(newReq(t)); err != nil {
+		t.Fatalf("first RoundTrip: %v", err)
+	}
+
+	resp, err := transport.RoundTrip(newReq(t))
+	if err != nil {
+		t.Fatalf("second RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d (304 should be translated to a 200 with the cached body)", resp.StatusCode, http.StatusOK)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"hello":"world"}` {
+		t.Errorf("body = %q, want the cached body replayed", body)
+	}
+	if transport.Hits != 1 || transport.Misses != 1 {
+		t.Errorf("Hits=%d Misses=%d, want Hits=1 Misses=1", transport.Hits, transport.Misses)
+	}
+
+	secondReq := base.requests[1]
+	if got := secondReq.Header.Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("If-None-Match = %q, want the ETag stored from the first response", got)
+	}
+}
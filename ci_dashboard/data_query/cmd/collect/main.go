@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command collect runs a single full scan of every configured repo's
+// GitHub Actions workflow runs and exits. For continuous collection via
+// webhooks instead of polling, see cmd/serve.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"testingdashboard/m/v2/collector"
+)
+
+func main() {
+	cfg := collector.LoadConfigFromEnv()
+
+	ctx := context.Background()
+	client, err := collector.NewGithubClient(ctx)
+	if err != nil {
+		log.Fatalf("Failed to construct GitHub Client %v", err)
+	}
+
+	targets, err := collector.ResolveTargets(ctx, client)
+	if err != nil {
+		log.Fatalf("Error resolving collection targets: %v", err)
+	}
+	log.Printf("Collecting workflow run data for %d repo(s)\n", len(targets))
+
+	collector.CollectAll(ctx, client, targets, cfg)
+
+	collector.PrintSummary()
+	fmt.Println("Workflow run data successfully gathered and stored.")
+}
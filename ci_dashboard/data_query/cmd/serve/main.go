@@ -0,0 +1,206 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command serve runs a long-lived HTTP service that keeps workflow run data
+// up to date from GitHub webhook deliveries instead of polling every
+// workflow on a fixed schedule. A full scan still runs periodically as a
+// fallback, to pick up anything a dropped or missed webhook delivery would
+// otherwise leave stale.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v52/github"
+
+	"testingdashboard/m/v2/collector"
+)
+
+const defaultReconcileInterval = time.Hour
+
+func main() {
+	cfg := collector.LoadConfigFromEnv()
+
+	ctx := context.Background()
+	client, err := collector.NewGithubClient(ctx)
+	if err != nil {
+		log.Fatalf("Failed to construct GitHub Client %v", err)
+	}
+
+	go reconcileLoop(ctx, client, cfg, reconcileInterval())
+
+	secret := []byte(os.Getenv("GITHUB_WEBHOOK_SECRET"))
+	http.HandleFunc("/webhook", webhookHandler(client, cfg, secret))
+	http.HandleFunc("/healthz", healthzHandler)
+	http.HandleFunc("/metrics", metricsHandler)
+
+	addr := ":" + getStringEnvOrDefault("PORT", "8080")
+	log.Printf("Listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+// reconcileLoop runs the full-scan collection path on a fixed interval, to
+// catch anything a webhook delivery missed (GitHub doesn't guarantee
+// delivery, and the service may have been down when an event fired).
+func reconcileLoop(ctx context.Context, client *github.Client, cfg collector.Config, interval time.Duration) {
+	for {
+		targets, err := collector.ResolveTargets(ctx, client)
+		if err != nil {
+			log.Printf("Error resolving reconciliation targets: %v", err)
+		} else {
+			log.Printf("Reconciling: full scan of %d repo(s)", len(targets))
+			collector.CollectAll(ctx, client, targets, cfg)
+			collector.PrintSummary()
+		}
+		time.Sleep(interval)
+	}
+}
+
+// webhookHandler validates the X-Hub-Signature-256 header against secret,
+// then upserts the run named in workflow_run and workflow_job events into
+// the on-disk store. Other event types are acknowledged and ignored; a
+// payload ParseWebHook can't parse, including an event type it doesn't
+// know, is rejected with 400.
+func webhookHandler(client *github.Client, cfg collector.Config, secret []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := github.ValidatePayload(r, secret)
+		if err != nil {
+			log.Printf("Rejecting webhook delivery: %v", err)
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		event, err := github.ParseWebHook(github.WebHookType(r), payload)
+		if err != nil {
+			http.Error(w, "cannot parse event", http.StatusBadRequest)
+			return
+		}
+
+		// Not r.Context(): it's canceled the moment this handler returns, which
+		// happens right after the goroutines below are started, so the upserts
+		// need a context that outlives the request.
+		ctx := context.Background()
+		switch e := event.(type) {
+		case *github.WorkflowRunEvent:
+			owner, repo := e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName()
+			workflowID, runID := e.GetWorkflow().GetID(), e.GetWorkflowRun().GetID()
+			go upsertRun(ctx, client, cfg, owner, repo, workflowID, runID)
+		case *github.WorkflowJobEvent:
+			owner, repo := e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName()
+			go upsertJob(ctx, client, cfg, owner, repo, e.GetWorkflowJob().GetRunID())
+		default:
+			log.Printf("Ignoring unhandled webhook event type %s", github.WebHookType(r))
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// upsertRun handles a workflow_run event, which already carries the
+// workflow ID, so it can go straight to collector.UpsertRun.
+func upsertRun(ctx context.Context, client *github.Client, cfg collector.Config, owner, repo string, workflowID, runID int64) {
+	if err := collector.UpsertRun(ctx, client, cfg, owner, repo, workflowID, runID); err != nil {
+		log.Printf("Error upserting %s/%s run %d: %v", owner, repo, runID, err)
+	}
+}
+
+// upsertJob handles a workflow_job event, which only carries a run ID, so
+// the run is fetched first to learn which workflow it belongs to.
+func upsertJob(ctx context.Context, client *github.Client, cfg collector.Config, owner, repo string, runID int64) {
+	run, _, err := client.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
+	if err != nil {
+		log.Printf("Error getting run %d for %s/%s: %v", runID, owner, repo, err)
+		return
+	}
+	collector.TotalRequests += 1
+
+	if err := collector.UpsertRun(ctx, client, cfg, owner, repo, run.GetWorkflowID(), runID); err != nil {
+		log.Printf("Error upserting %s/%s run %d: %v", owner, repo, runID, err)
+	}
+}
+
+// healthzHandler reports liveness: if the process can handle this request,
+// it's healthy. There's no dependency check because the webhook handler and
+// reconciliation loop already log and continue on GitHub API errors rather
+// than dying.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+// metricsHandler exposes values PrintSummary also logs (API requests,
+// retries, remaining quota, cache hits and misses) in Prometheus text
+// format, so the service's own request volume and cache effectiveness can
+// be scraped alongside the workflow data it collects.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+
+	b.WriteString("# HELP github_api_requests_total GitHub API requests made since startup.\n")
+	b.WriteString("# TYPE github_api_requests_total counter\n")
+	fmt.Fprintf(&b, "github_api_requests_total %d\n", collector.TotalRequests)
+
+	if collector.RateGovernor != nil {
+		b.WriteString("# HELP github_api_retries_total GitHub API requests retried after a rate limit or server error.\n")
+		b.WriteString("# TYPE github_api_retries_total counter\n")
+		fmt.Fprintf(&b, "github_api_retries_total %d\n", collector.RateGovernor.RetryCount)
+
+		b.WriteString("# HELP github_api_rate_limit_remaining Remaining GitHub API rate limit quota.\n")
+		b.WriteString("# TYPE github_api_rate_limit_remaining gauge\n")
+		fmt.Fprintf(&b, "github_api_rate_limit_remaining %d\n", collector.RateGovernor.Remaining())
+	}
+
+	if collector.HTTPCache != nil {
+		b.WriteString("# HELP github_http_cache_hits_total Conditional requests served from the local cache.\n")
+		b.WriteString("# TYPE github_http_cache_hits_total counter\n")
+		fmt.Fprintf(&b, "github_http_cache_hits_total %d\n", collector.HTTPCache.Hits)
+
+		b.WriteString("# HELP github_http_cache_misses_total Conditional requests that required a full response.\n")
+		b.WriteString("# TYPE github_http_cache_misses_total counter\n")
+		fmt.Fprintf(&b, "github_http_cache_misses_total %d\n", collector.HTTPCache.Misses)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprint(w, b.String())
+}
+
+// reconcileInterval reads RECONCILE_INTERVAL (a Go duration string, e.g.
+// "30m"), defaulting to defaultReconcileInterval.
+func reconcileInterval() time.Duration {
+	raw := os.Getenv("RECONCILE_INTERVAL")
+	if raw == "" {
+		return defaultReconcileInterval
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("Invalid RECONCILE_INTERVAL (%q), using default %s", raw, defaultReconcileInterval)
+		return defaultReconcileInterval
+	}
+	return d
+}
+
+// getStringEnvOrDefault reads a string environment variable, falling back to
+// def if it is unset.
+func getStringEnvOrDefault(envName, def string) string {
+	rawValue, ok := os.LookupEnv(envName)
+	if !ok {
+		return def
+	}
+	return rawValue
+}
@@ -0,0 +1,963 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package collector gathers GitHub Actions workflow run data and writes it
+// to disk. It is shared by cmd/collect, which runs one full scan and exits,
+// and cmd/serve, which keeps the same store up to date via webhooks with a
+// full scan as a periodic fallback.
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v52/github"
+	"golang.org/x/oauth2"
+
+	"testingdashboard/m/v2/cache"
+	"testingdashboard/m/v2/config"
+	"testingdashboard/m/v2/download"
+	"testingdashboard/m/v2/export"
+	"testingdashboard/m/v2/model"
+	"testingdashboard/m/v2/ratelimit"
+)
+
+const (
+	defaultBranch             = "main"
+	defaultMaxRunsPerWorkflow = 15
+	defaultPruneAfterDays     = 30
+	defaultRepoConcurrency    = 4
+	defaultRateLimitThreshold = 100
+	defaultArtifactMaxMB      = 50
+	// stateFile is the canonical on-disk round-trip state for a repo. It's
+	// written unconditionally by writeRepoData, independent of
+	// cfg.ExportFormats/cfg.ShardOutput (which only control user-facing
+	// output), so loadPreviousData always has prior history to merge against
+	// on the next run even when the "json" export is sharded or disabled.
+	stateFile       = ".state.json"
+	dataDir         = "data"
+	daysToConsider  = 14
+	channelLimiting = 10
+	httpCacheFile   = ".cache/http_cache.json"
+)
+
+// WorkflowRun and WorkflowRunData are aliases for the model package's types,
+// which exist so export.Exporter implementations can consume this data
+// without importing package collector.
+type (
+	WorkflowRun     = model.WorkflowRun
+	WorkflowRunData = model.WorkflowRunData
+)
+
+// githubAppConfig contains data needed to authenticate with a Github App
+// Installation.
+type githubAppConfig struct {
+	GithubAppID             int64
+	GithubAppInstallationID int64
+	GithubAppPrivateKey     string
+}
+
+// TotalRequests tracks the total GitHub API requests made, for debugging and
+// for the /metrics endpoint.
+var TotalRequests int = 0
+
+// RateGovernor bounds how many requests we have in flight at once and backs
+// off on 403/429. Set by LoadConfigFromEnv before first use.
+var RateGovernor *ratelimit.Governor
+
+// HTTPCache is the conditional-request cache wrapping the GitHub transport,
+// set up in NewGithubClient. Its Hits/Misses are reported by PrintSummary
+// and the /metrics endpoint.
+var HTTPCache *cache.Transport
+
+// How many runs we retain per workflow, overridable via MAX_RUNS_PER_WORKFLOW.
+var maxRunsPerWorkflow = defaultMaxRunsPerWorkflow
+
+// workflowsListPath, workflowRunsPath, and jobsPath match the three list
+// endpoints we cache responses for, capturing (owner, repo), (owner, repo,
+// workflowID), and (owner, repo, runID) respectively.
+var (
+	workflowsListPath = regexp.MustCompile(`^/repos/([^/]+)/([^/]+)/actions/workflows$`)
+	workflowRunsPath  = regexp.MustCompile(`^/repos/([^/]+)/([^/]+)/actions/workflows/(\d+)/runs$`)
+	jobsPath          = regexp.MustCompile(`^/repos/([^/]+)/([^/]+)/actions/runs/(\d+)/jobs$`)
+)
+
+// volatileWorkflowRunsParams lists workflow-runs query params that change on
+// every call without reflecting a change in the underlying data, so they're
+// dropped before deriving a cache key. Created in particular encodes a
+// "created after" cutoff of time.Now() minus daysToConsider, which rolls
+// forward every calendar day and would otherwise cache-bust this, the
+// priciest endpoint we call, once every 24 hours.
+var volatileWorkflowRunsParams = []string{"created"}
+
+// githubCacheKey derives a cache.Store key for requests to the
+// list-workflows, workflow-runs, and workflow-jobs list endpoints; every
+// other request is left uncached.
+func githubCacheKey(req *http.Request) (string, bool) {
+	if req.Method != http.MethodGet {
+		return "", false
+	}
+	if m := workflowsListPath.FindStringSubmatch(req.URL.Path); m != nil {
+		return cache.RepoKey(m[1], m[2]) + "?" + req.URL.RawQuery, true
+	}
+	if m := workflowRunsPath.FindStringSubmatch(req.URL.Path); m != nil {
+		workflowID, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return cache.RepoWorkflowKey(m[1], m[2], workflowID) + "?" + stableQuery(req.URL, volatileWorkflowRunsParams...), true
+	}
+	if m := jobsPath.FindStringSubmatch(req.URL.Path); m != nil {
+		runID, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return cache.RepoRunKey(m[1], m[2], runID) + "?" + req.URL.RawQuery, true
+	}
+	return "", false
+}
+
+// stableQuery re-encodes u's query string with drop removed, so volatile
+// params don't prevent an otherwise-identical request from hitting the
+// cache.
+func stableQuery(u *url.URL, drop ...string) string {
+	values := u.Query()
+	for _, key := range drop {
+		values.Del(key)
+	}
+	return values.Encode()
+}
+
+// Config bundles the environment-derived settings shared by a one-shot
+// collection run and the long-running service, so both build it the same
+// way via LoadConfigFromEnv.
+type Config struct {
+	ExportFormats   []string
+	PruneAfterDays  int
+	ShardOutput     bool
+	RepoConcurrency int
+	DownloadOpts    download.Options
+}
+
+// LoadConfigFromEnv reads Config from the environment and initializes
+// RateGovernor and the package's maxRunsPerWorkflow setting. It must be
+// called before NewGithubClient.
+func LoadConfigFromEnv() Config {
+	maxRunsPerWorkflow = getIntEnvOrDefault("MAX_RUNS_PER_WORKFLOW", defaultMaxRunsPerWorkflow)
+	RateGovernor = ratelimit.NewGovernor(channelLimiting, getIntEnvOrDefault("RATE_LIMIT_THRESHOLD", defaultRateLimitThreshold))
+	return Config{
+		ExportFormats:   readExportFormats(),
+		PruneAfterDays:  getIntEnvOrDefault("WORKFLOW_PRUNE_AFTER_DAYS", defaultPruneAfterDays),
+		ShardOutput:     getBoolEnvOrDefault("SHARD_OUTPUT", false),
+		RepoConcurrency: getIntEnvOrDefault("REPO_CONCURRENCY", defaultRepoConcurrency),
+		DownloadOpts: download.Options{
+			CollectArtifacts: getBoolEnvOrDefault("COLLECT_ARTIFACTS", false),
+			CollectLogs:      getStringEnvOrDefault("COLLECT_LOGS", download.CollectLogsNever),
+			MaxBytesPerRun:   int64(getIntEnvOrDefault("ARTIFACT_MAX_MB", defaultArtifactMaxMB)) * 1024 * 1024,
+		},
+	}
+}
+
+// PrintSummary logs the running totals tracked across one or more
+// collection passes: API requests made, HTTP cache hit rate, and rate
+// governor activity.
+func PrintSummary() {
+	fmt.Printf("Number of api requestse made %d\n", TotalRequests)
+	if HTTPCache != nil {
+		if err := HTTPCache.Store.Save(); err != nil {
+			log.Printf("Error saving HTTP cache: %v", err)
+		}
+		fmt.Printf("HTTP cache hits: %d, misses: %d\n", HTTPCache.Hits, HTTPCache.Misses)
+	}
+	fmt.Printf("GitHub API requests: %d, retries: %d, longest wait: %s, remaining quota: %d\n",
+		RateGovernor.RequestCount, RateGovernor.RetryCount, RateGovernor.LongestWait, RateGovernor.Remaining())
+}
+
+// RepoTarget is one (owner, repo, branch) to collect workflow run data for.
+type RepoTarget struct {
+	Owner  string
+	Repo   string
+	Branch string
+}
+
+// ResolveTargets builds the list of repos to collect. If CONFIG_FILE is set
+// it's expanded (including org-wide targets); otherwise it falls back to a
+// single target built from GITHUB_REPOSITORY_ORG/NAME/TARGET_BRANCH.
+func ResolveTargets(ctx context.Context, client *github.Client) ([]RepoTarget, error) {
+	configFile := os.Getenv("CONFIG_FILE")
+	if configFile == "" {
+		owner, repo, branch := readSingleRepoConfig()
+		return []RepoTarget{{Owner: owner, Repo: repo, Branch: branch}}, nil
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", configFile, err)
+	}
+
+	var targets []RepoTarget
+	for _, t := range cfg.Targets {
+		branchPatterns := t.Branches
+		if len(branchPatterns) == 0 {
+			branchPatterns = []string{defaultBranch}
+		}
+
+		if t.Org {
+			repoNames, err := listOrgRepos(ctx, client, t.Owner, t.Exclude, t.Topics)
+			if err != nil {
+				return nil, fmt.Errorf("listing repos for org %s: %w", t.Owner, err)
+			}
+			for _, repoName := range repoNames {
+				branches, err := resolveBranches(ctx, client, t.Owner, repoName, branchPatterns)
+				if err != nil {
+					return nil, fmt.Errorf("resolving branches for %s/%s: %w", t.Owner, repoName, err)
+				}
+				for _, branch := range branches {
+					targets = append(targets, RepoTarget{Owner: t.Owner, Repo: repoName, Branch: branch})
+				}
+			}
+			continue
+		}
+
+		branches, err := resolveBranches(ctx, client, t.Owner, t.Repo, branchPatterns)
+		if err != nil {
+			return nil, fmt.Errorf("resolving branches for %s/%s: %w", t.Owner, t.Repo, err)
+		}
+		for _, branch := range branches {
+			targets = append(targets, RepoTarget{Owner: t.Owner, Repo: t.Repo, Branch: branch})
+		}
+	}
+	return targets, nil
+}
+
+// resolveBranches expands each pattern in patterns against repo's real
+// branches via filepath.Match — the same glob semantics listOrgRepos uses
+// for repo names — so a "release-*"-style entry actually matches something
+// instead of being passed straight through as an exact-match query filter
+// GitHub doesn't support. Patterns with no glob metacharacters are kept
+// as-is without an API call, since GitHub's exact-match filter already
+// handles them.
+func resolveBranches(ctx context.Context, client *github.Client, owner, repo string, patterns []string) ([]string, error) {
+	var literal, globs []string
+	for _, pattern := range patterns {
+		if strings.ContainsAny(pattern, "*?[") {
+			globs = append(globs, pattern)
+		} else {
+			literal = append(literal, pattern)
+		}
+	}
+	if len(globs) == 0 {
+		return literal, nil
+	}
+
+	opts := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	matched := literal
+	for {
+		branches, resp, err := client.Repositories.ListBranches(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		TotalRequests += 1
+
+	branchLoop:
+		for _, branch := range branches {
+			for _, pattern := range globs {
+				if ok, _ := filepath.Match(pattern, branch.GetName()); ok {
+					matched = append(matched, branch.GetName())
+					continue branchLoop
+				}
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return matched, nil
+}
+
+// listOrgRepos lists every repo in owner, skipping ones whose name matches
+// an exclude glob, or that have none of topics (when topics is non-empty).
+func listOrgRepos(ctx context.Context, client *github.Client, owner string, exclude, topics []string) ([]string, error) {
+	opts := &github.RepositoryListByOrgOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var names []string
+	for {
+		repos, resp, err := client.Repositories.ListByOrg(ctx, owner, opts)
+		if err != nil {
+			return nil, err
+		}
+		TotalRequests += 1
+
+	reposLoop:
+		for _, repo := range repos {
+			for _, pattern := range exclude {
+				if matched, _ := filepath.Match(pattern, repo.GetName()); matched {
+					continue reposLoop
+				}
+			}
+			if len(topics) > 0 && !hasAnyTopic(repo.Topics, topics) {
+				continue
+			}
+			names = append(names, repo.GetName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return names, nil
+}
+
+// hasAnyTopic reports whether repoTopics contains at least one of want.
+func hasAnyTopic(repoTopics, want []string) bool {
+	set := make(map[string]bool, len(repoTopics))
+	for _, t := range repoTopics {
+		set[t] = true
+	}
+	for _, w := range want {
+		if set[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectAll collects every target, bounded by cfg.RepoConcurrency in-flight
+// repos at once. Errors collecting an individual repo are logged, not
+// returned, so one bad repo doesn't stop the rest.
+func CollectAll(ctx context.Context, client *github.Client, targets []RepoTarget, cfg Config) {
+	sem := make(chan struct{}, cfg.RepoConcurrency)
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target RepoTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := CollectRepo(ctx, client, target, cfg); err != nil {
+				log.Printf("Error collecting %s/%s: %v", target.Owner, target.Repo, err)
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+// CollectRepo gathers and stores workflow run data for a single repo
+// target, under data/<owner>/<repo>/.
+func CollectRepo(ctx context.Context, client *github.Client, target RepoTarget, cfg Config) error {
+	log.Printf("Getting data for Org:%s Repo:%s Branch:%s\n", target.Owner, target.Repo, target.Branch)
+	repoDir := filepath.Join(dataDir, target.Owner, target.Repo)
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		return err
+	}
+
+	workflows, err := getWorkflows(ctx, client, target.Owner, target.Repo)
+	if err != nil {
+		return fmt.Errorf("getting workflows: %w", err)
+	}
+
+	newData := make(map[int64]*WorkflowRunData)
+	var wg sync.WaitGroup
+	for _, workflow := range workflows {
+		wg.Add(1)
+		go func(workflow *github.Workflow) {
+			defer wg.Done()
+			runs, err := getWorkflowRuns(ctx, client, target.Owner, target.Repo, workflow.GetID(), target.Branch, repoDir, cfg.DownloadOpts)
+			if err != nil {
+				log.Printf("Error getting runs for workflow %s (ID: %d): %v", workflow.GetName(), workflow.GetID(), err)
+				return
+			}
+
+			newData[workflow.GetID()] = &WorkflowRunData{
+				WorkflowID:    workflow.GetID(),
+				WorkflowName:  workflow.GetName(),
+				WorkflowURL:   workflow.GetHTMLURL(),
+				Runs:          runs,
+				LastUpdatedAt: time.Now(),
+			}
+		}(workflow)
+	}
+	wg.Wait()
+
+	return writeRepoData(repoDir, newData, cfg)
+}
+
+// UpsertRun fetches a single run (and its jobs) by ID and merges it into the
+// on-disk store for owner/repo, without rescanning every workflow. It's used
+// by the webhook handler so a workflow_run/workflow_job event only costs a
+// couple of API calls instead of a full repo scan.
+func UpsertRun(ctx context.Context, client *github.Client, cfg Config, owner, repo string, workflowID, runID int64) error {
+	repoDir := filepath.Join(dataDir, owner, repo)
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		return err
+	}
+
+	workflow, _, err := client.Actions.GetWorkflowByID(ctx, owner, repo, workflowID)
+	if err != nil {
+		return fmt.Errorf("getting workflow %d: %w", workflowID, err)
+	}
+	TotalRequests += 1
+
+	run, _, err := client.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
+	if err != nil {
+		return fmt.Errorf("getting run %d: %w", runID, err)
+	}
+	TotalRequests += 1
+
+	// Nil out data we don't need that greatly bloats the size of the data
+	run.Repository = nil
+	run.HeadRepository = nil
+	run.Actor = nil
+	if run.HeadCommit != nil {
+		run.HeadCommit.Author = nil
+	}
+
+	workflowRun := &WorkflowRun{Run: run, Jobs: map[string]*github.WorkflowJob{}}
+	jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{Filter: "latest", ListOptions: github.ListOptions{PerPage: 100}})
+	if err != nil {
+		log.Printf("Error getting jobs for run (ID: %d): %v", runID, err)
+	} else {
+		for _, job := range jobs.Jobs {
+			job.Steps = nil
+			workflowRun.Jobs[job.GetName()] = job
+		}
+		TotalRequests += 1
+	}
+
+	if cfg.DownloadOpts.CollectArtifacts || cfg.DownloadOpts.CollectLogs != download.CollectLogsNever {
+		runDir := filepath.Join(repoDir, strconv.FormatInt(runID, 10))
+		artifacts, err := download.Run(ctx, client, owner, repo, workflowRun, runDir, cfg.DownloadOpts)
+		if err != nil {
+			log.Printf("Error downloading artifacts/logs for run %d: %v", runID, err)
+		}
+		workflowRun.Artifacts = artifacts
+	}
+
+	newData := map[int64]*WorkflowRunData{
+		workflowID: {
+			WorkflowID:    workflowID,
+			WorkflowName:  workflow.GetName(),
+			WorkflowURL:   workflow.GetHTMLURL(),
+			Runs:          []*WorkflowRun{workflowRun},
+			LastUpdatedAt: time.Now(),
+		},
+	}
+
+	return writeRepoData(repoDir, newData, cfg)
+}
+
+// repoLocks serializes the read-merge-write cycle in writeRepoData per repo
+// directory. Without it, two concurrent updates for the same repo (a burst
+// of webhook deliveries, or a webhook landing mid-reconcile) can each load
+// the same prior state, merge independently, and overwrite each other's
+// result — last writer wins, silently dropping one update.
+var repoLocks sync.Map
+
+// lockRepo returns the mutex guarding repoDir's data file, creating one on
+// first use.
+func lockRepo(repoDir string) *sync.Mutex {
+	mu, _ := repoLocks.LoadOrStore(repoDir, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// writeRepoData loads the previously stored state for repoDir, merges
+// newData into it, and writes the result out in every format
+// cfg.ExportFormats requests plus the canonical state file. The whole
+// read-merge-write cycle is serialized per repoDir via repoLocks, since it's
+// shared by CollectRepo's full-scan path, UpsertRun's single-run path, and
+// cmd/serve's reconcile loop, any of which may run concurrently for the same
+// repo.
+func writeRepoData(repoDir string, newData map[int64]*WorkflowRunData, cfg Config) error {
+	mu := lockRepo(repoDir)
+	mu.Lock()
+	defer mu.Unlock()
+
+	previousData := loadPreviousData(filepath.Join(repoDir, stateFile))
+	mergedData := mergeData(previousData, newData, cfg.PruneAfterDays)
+
+	if err := saveState(repoDir, mergedData); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	exporters, err := export.Resolve(cfg.ExportFormats, repoDir)
+	if err != nil {
+		return fmt.Errorf("resolving EXPORT_FORMATS: %w", err)
+	}
+	for i, formatName := range cfg.ExportFormats {
+		if formatName == "json" && cfg.ShardOutput {
+			if err := saveShardedData(mergedData, repoDir); err != nil {
+				return fmt.Errorf("saving sharded data: %w", err)
+			}
+			continue
+		}
+		if err := exporters[i].Write(mergedData); err != nil {
+			return fmt.Errorf("writing %s export: %w", formatName, err)
+		}
+	}
+	return nil
+}
+
+// newCachingTransport builds the conditional-request cache used by both auth
+// modes in NewGithubClient, wrapping base with the rate limit governor and
+// then the ETag cache. Every request, including a conditional one that the
+// server answers with 304, is gated and retried by the governor; the cache
+// only sees the final response.
+func newCachingTransport(base http.RoundTripper) (*cache.Transport, error) {
+	store, err := cache.NewStore(httpCacheFile)
+	if err != nil {
+		return nil, err
+	}
+	governed := &ratelimit.Transport{Base: base, Governor: RateGovernor}
+	return &cache.Transport{Base: governed, Store: store, KeyFunc: githubCacheKey}, nil
+}
+
+// NewGithubClient creates a Github client authenticated either with a
+// GH_TOKEN or a GitHub App installation. Whichever auth mode is used, the
+// same client and credentials are reused for every follow-up API call,
+// including ones triggered by webhook events in cmd/serve.
+func NewGithubClient(ctx context.Context) (*github.Client, error) {
+	if githubToken, ok := os.LookupEnv("GH_TOKEN"); ok {
+		fmt.Println("Using GitHub Token to authenticate")
+		cachingTransport, err := newCachingTransport(http.DefaultTransport)
+		if err != nil {
+			return nil, err
+		}
+		HTTPCache = cachingTransport
+
+		ts := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: githubToken},
+		)
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: cachingTransport})
+		tc := oauth2.NewClient(ctx, ts)
+		return github.NewClient(tc), nil
+	}
+
+	fmt.Println("Using GitHub App to authenticate")
+	appConfig, err := readGithubAppConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Github App ID %v installation ID %v", appConfig.GithubAppID, appConfig.GithubAppInstallationID)
+	// Wrap the shared transport for use with the app ID authenticating with installation ID.
+	itr, err := ghinstallation.New(http.DefaultTransport, appConfig.GithubAppID, appConfig.GithubAppInstallationID, []byte(appConfig.GithubAppPrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	cachingTransport, err := newCachingTransport(itr)
+	if err != nil {
+		return nil, err
+	}
+	HTTPCache = cachingTransport
+
+	// Use installation transport, wrapped with the conditional-request cache, with client.
+	return github.NewClient(&http.Client{Transport: cachingTransport}), nil
+}
+
+// readSingleRepoConfig reads the single-repo collection config from
+// environment variables. It's the fallback used when CONFIG_FILE isn't set.
+func readSingleRepoConfig() (string, string, string) {
+	repoOwner := os.Getenv("GITHUB_REPOSITORY_ORG")
+	repoName := os.Getenv("GITHUB_REPOSITORY_NAME")
+	targetBranch := os.Getenv("TARGET_BRANCH")
+
+	if repoOwner == "" {
+		log.Fatal("Missing required environment variables: GITHUB_REPOSITORY_ORG")
+	}
+
+	if repoName == "" {
+		log.Fatal("Missing required environment variables: GITHUB_REPOSITORY_NAME")
+	}
+
+	if targetBranch == "" {
+		targetBranch = defaultBranch
+		log.Printf("TARGET_BRANCH not set, using default: %s", defaultBranch)
+	}
+
+	return repoOwner, repoName, targetBranch
+}
+
+// readExportFormats reads the comma-separated EXPORT_FORMATS environment
+// variable, defaulting to export.DefaultFormat for back-compat.
+func readExportFormats() []string {
+	raw := os.Getenv("EXPORT_FORMATS")
+	if raw == "" {
+		return []string{export.DefaultFormat}
+	}
+
+	var formats []string
+	for _, format := range strings.Split(raw, ",") {
+		formats = append(formats, strings.TrimSpace(format))
+	}
+	return formats
+}
+
+// readGithubAppConfig reads configuration from environment variables for
+// authenticating using a Github App.
+func readGithubAppConfig() (githubAppConfig, error) {
+	githubAppId, err := getIntEnvironmentVariable("GITHUB_APP_ID")
+	if err != nil {
+		return githubAppConfig{}, err
+	}
+
+	githubAppInstallationId, err := getIntEnvironmentVariable("GITHUB_APP_INSTALLATION_ID")
+	if err != nil {
+		return githubAppConfig{}, err
+	}
+
+	githubAppPrivateKey := os.Getenv("GITHUB_APP_PRIVATE_KEY")
+	if githubAppPrivateKey == "" {
+		return githubAppConfig{}, fmt.Errorf("missing required environment variables: GITHUB_APP_PRIVATE_KEY")
+	}
+
+	return githubAppConfig{githubAppId, githubAppInstallationId, githubAppPrivateKey}, nil
+}
+
+func getIntEnvironmentVariable(envName string) (int64, error) {
+	rawValue, ok := os.LookupEnv(envName)
+	if !ok {
+		return 0, fmt.Errorf("missing environment variable: %v", envName)
+	}
+
+	intValue, err := strconv.ParseInt(rawValue, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %v with error: %v", envName, err)
+	}
+	return intValue, nil
+}
+
+// getIntEnvOrDefault reads an integer environment variable, falling back to
+// def if it is unset or fails to parse.
+func getIntEnvOrDefault(envName string, def int) int {
+	rawValue, ok := os.LookupEnv(envName)
+	if !ok {
+		return def
+	}
+	value, err := strconv.Atoi(rawValue)
+	if err != nil {
+		log.Printf("Invalid value for %s (%q), using default %d", envName, rawValue, def)
+		return def
+	}
+	return value
+}
+
+// getBoolEnvOrDefault reads a boolean environment variable, falling back to
+// def if it is unset or fails to parse.
+func getBoolEnvOrDefault(envName string, def bool) bool {
+	rawValue, ok := os.LookupEnv(envName)
+	if !ok {
+		return def
+	}
+	value, err := strconv.ParseBool(rawValue)
+	if err != nil {
+		log.Printf("Invalid value for %s (%q), using default %v", envName, rawValue, def)
+		return def
+	}
+	return value
+}
+
+// getStringEnvOrDefault reads a string environment variable, falling back to
+// def if it is unset.
+func getStringEnvOrDefault(envName, def string) string {
+	rawValue, ok := os.LookupEnv(envName)
+	if !ok {
+		return def
+	}
+	return rawValue
+}
+
+// getWorkflows retrieves all workflows for a repository.
+func getWorkflows(ctx context.Context, client *github.Client, owner, repo string) ([]*github.Workflow, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var allWorkflows []*github.Workflow
+	for {
+		workflows, resp, err := client.Actions.ListWorkflows(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		TotalRequests += 1
+		allWorkflows = append(allWorkflows, workflows.Workflows...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return allWorkflows, nil
+}
+
+// getWorkflowRuns retrieves the latest runs for a specific workflow on a given branch.
+func getWorkflowRuns(ctx context.Context, client *github.Client, owner, repo string, workflowID int64, branch string, repoDir string, downloadOpts download.Options) ([]*WorkflowRun, error) {
+	dateCreatedTerm := time.Now().AddDate(0, 0, -daysToConsider)
+	optsCreated := dateCreatedTerm.Format(">2006-01-02")
+	log.Printf("Date opts: %s\n", optsCreated)
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:              branch,
+		ListOptions:         github.ListOptions{PerPage: maxRunsPerWorkflow},
+		ExcludePullRequests: true,
+		Created:             optsCreated,
+	}
+	var allRuns []*WorkflowRun
+	for {
+		runs, resp, err := client.Actions.ListWorkflowRunsByID(ctx, owner, repo, workflowID, opts)
+		if err != nil {
+			return nil, err
+		}
+		TotalRequests += 1
+		for _, run := range runs.WorkflowRuns {
+			// Nil out data we don't need that greatly bloats the size of the data
+			run.Repository = nil
+			run.HeadRepository = nil
+			run.Actor = nil
+
+			// Nil out author data so we don't hold names and emails in this json
+			if run.HeadCommit != nil {
+				run.HeadCommit.Author = nil
+			}
+
+			allRuns = append(allRuns, &WorkflowRun{Run: run, Jobs: map[string]*github.WorkflowJob{}})
+		}
+		if len(allRuns) >= maxRunsPerWorkflow {
+			break
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+		if len(allRuns) >= maxRunsPerWorkflow {
+			break
+		}
+	}
+
+	// Remove any extras, we are already hitting the api hard
+	if len(allRuns) >= maxRunsPerWorkflow {
+		allRuns = allRuns[:maxRunsPerWorkflow]
+	}
+
+	// Grab the WorkFlowJob data for each run. Concurrency across all of these
+	// (and every other GitHub API call) is bounded by RateGovernor, not a
+	// fixed-size channel, so it's safe to fan out one goroutine per run here.
+	var wg2 sync.WaitGroup
+	for i, run := range allRuns {
+		wg2.Add(1)
+
+		go func(i int, run *WorkflowRun) {
+			defer wg2.Done()
+			// we ingore resp, we are not pulling more than 100 jobs, that really shouldn't be happening and if it is we arn't going to be able to visulize that anyway.
+			jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, run.Run.GetID(), &github.ListWorkflowJobsOptions{Filter: "latest", ListOptions: github.ListOptions{PerPage: 100}})
+			if err != nil {
+				log.Printf("Error getting jobs for run %d (ID: %d): %v", i, run.Run.GetID(), err)
+				return
+			}
+			for _, job := range jobs.Jobs {
+				// Remote the steps, while this data could be useful at some point we do not currently visuilizae and its quite large.
+				// A user would find step level data by following the link to the job itself
+				job.Steps = nil
+
+				// Check if allRuns already contains this job name, if so we only release it if the RunAttempt is larger
+				if _, ok := run.Jobs[*job.Name]; ok {
+					if run.Jobs[*job.Name].GetRunAttempt() < job.GetRunAttempt() {
+						run.Jobs[*job.Name] = job
+					}
+				} else {
+					allRuns[i].Jobs[*job.Name] = job
+				}
+			}
+			TotalRequests += 1
+
+			if downloadOpts.CollectArtifacts || downloadOpts.CollectLogs != download.CollectLogsNever {
+				runDir := filepath.Join(repoDir, strconv.FormatInt(run.Run.GetID(), 10))
+				artifacts, err := download.Run(ctx, client, owner, repo, run, runDir, downloadOpts)
+				if err != nil {
+					log.Printf("Error downloading artifacts/logs for run %d: %v", run.Run.GetID(), err)
+				}
+				run.Artifacts = artifacts
+			}
+		}(i, run)
+	}
+	wg2.Wait()
+
+	return allRuns, nil
+}
+
+// loadPreviousData loads workflow run data from path.
+func loadPreviousData(path string) map[int64]*WorkflowRunData {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Println("No previous data file found.")
+		} else {
+			log.Printf("Error reading previous data file: %v", err)
+		}
+		return make(map[int64]*WorkflowRunData)
+	}
+
+	var previousData map[int64]*WorkflowRunData
+	err = json.Unmarshal(data, &previousData)
+	if err != nil {
+		log.Printf("Error unmarshalling previous data: %v", err)
+		return make(map[int64]*WorkflowRunData)
+	}
+
+	return previousData
+}
+
+// saveState persists mergedData to repoDir's canonical state file. It's
+// written unconditionally, independent of cfg.ExportFormats/cfg.ShardOutput,
+// so loadPreviousData always has prior history to merge against on the next
+// run even when the "json" export is sharded or disabled.
+func saveState(repoDir string, data map[int64]*WorkflowRunData) error {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(repoDir, stateFile), jsonData, 0644)
+}
+
+// mergeData merges newly-collected workflow run data with data loaded from
+// disk. Workflows present in newData win, with their run history merged
+// against whatever was previously stored for them. Workflows missing from
+// newData (deleted, renamed, or simply not returned this pass) are kept
+// around until they haven't been refreshed for pruneAfterDays, at which
+// point they are dropped.
+func mergeData(previous, new map[int64]*WorkflowRunData, pruneAfterDays int) map[int64]*WorkflowRunData {
+	merged := make(map[int64]*WorkflowRunData, len(new))
+
+	for id, newEntry := range new {
+		prevEntry, ok := previous[id]
+		if !ok {
+			merged[id] = newEntry
+			continue
+		}
+		merged[id] = &WorkflowRunData{
+			WorkflowID:    newEntry.WorkflowID,
+			WorkflowName:  newEntry.WorkflowName,
+			WorkflowURL:   newEntry.WorkflowURL,
+			Runs:          mergeRuns(prevEntry.Runs, newEntry.Runs),
+			LastUpdatedAt: newEntry.LastUpdatedAt,
+		}
+	}
+
+	pruneAfter := time.Duration(pruneAfterDays) * 24 * time.Hour
+	for id, prevEntry := range previous {
+		if _, ok := new[id]; ok {
+			continue
+		}
+		if time.Since(prevEntry.LastUpdatedAt) > pruneAfter {
+			log.Printf("Pruning workflow %s (ID: %d), not seen since %s", prevEntry.WorkflowName, id, prevEntry.LastUpdatedAt)
+			continue
+		}
+		merged[id] = prevEntry
+	}
+
+	return merged
+}
+
+// mergeRuns combines previously stored runs with newly fetched runs, keyed
+// by run ID. When a run appears in both, the one with the higher RunAttempt
+// wins; ties are broken by UpdatedAt. The result is sorted by CreatedAt
+// descending and capped at maxRunsPerWorkflow.
+func mergeRuns(previous, new []*WorkflowRun) []*WorkflowRun {
+	byID := make(map[int64]*WorkflowRun, len(previous)+len(new))
+	for _, run := range previous {
+		byID[run.Run.GetID()] = run
+	}
+	for _, run := range new {
+		existing, ok := byID[run.Run.GetID()]
+		if !ok || isNewerRun(run.Run, existing.Run) {
+			byID[run.Run.GetID()] = run
+		}
+	}
+
+	combined := make([]*WorkflowRun, 0, len(byID))
+	for _, run := range byID {
+		combined = append(combined, run)
+	}
+	sort.Slice(combined, func(i, j int) bool {
+		return combined[i].Run.GetCreatedAt().After(combined[j].Run.GetCreatedAt().Time)
+	})
+
+	if len(combined) > maxRunsPerWorkflow {
+		combined = combined[:maxRunsPerWorkflow]
+	}
+	return combined
+}
+
+// isNewerRun reports whether a is a fresher copy of the same workflow run
+// than b: a higher RunAttempt wins outright, and ties fall back to UpdatedAt.
+func isNewerRun(a, b *github.WorkflowRun) bool {
+	if a.GetRunAttempt() != b.GetRunAttempt() {
+		return a.GetRunAttempt() > b.GetRunAttempt()
+	}
+	return a.GetUpdatedAt().After(b.GetUpdatedAt().Time)
+}
+
+// workflowIndexEntry describes one workflow's shard file, so dashboards can
+// discover what's available without reading every shard up front.
+type workflowIndexEntry struct {
+	WorkflowID    int64     `json:"workflow_id"`
+	WorkflowName  string    `json:"workflow_name"`
+	File          string    `json:"file"`
+	LastUpdatedAt time.Time `json:"last_updated_at"`
+}
+
+// saveShardedData writes one JSON file per workflow under dir, plus an
+// index.json summarizing them, so dashboards can load or refresh individual
+// workflows incrementally instead of re-reading a monolithic file.
+func saveShardedData(data map[int64]*WorkflowRunData, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	index := make([]workflowIndexEntry, 0, len(data))
+	for id, entry := range data {
+		file := fmt.Sprintf("%d.json", id)
+		jsonData, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, file), jsonData, 0644); err != nil {
+			return err
+		}
+		index = append(index, workflowIndexEntry{
+			WorkflowID:    id,
+			WorkflowName:  entry.WorkflowName,
+			File:          file,
+			LastUpdatedAt: entry.LastUpdatedAt,
+		})
+	}
+	sort.Slice(index, func(i, j int) bool { return index[i].WorkflowID < index[j].WorkflowID })
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "index.json"), indexData, 0644)
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v52/github"
+)
+
+func newRun(id int64, attempt int, created, updated time.Time) *WorkflowRun {
+	return &WorkflowRun{
+		Run: &github.WorkflowRun{
+			ID:         github.Int64(id),
+			RunAttempt: github.Int(attempt),
+			CreatedAt:  &github.Timestamp{Time: created},
+			UpdatedAt:  &github.Timestamp{Time: updated},
+		},
+		Jobs: map[string]*github.WorkflowJob{},
+	}
+}
+
+// TestMergeRunsRerunWins checks that a rerun (higher RunAttempt) replaces
+// the previously stored copy of the same run.
+func TestMergeRunsRerunWins(t *testing.T) {
+	now := time.Now()
+	previous := []*WorkflowRun{newRun(1, 1, now, now)}
+	fresh := []*WorkflowRun{newRun(1, 2, now, now.Add(time.Minute))}
+
+	merged := mergeRuns(previous, fresh)
+
+	if len(merged) != 1 {
+		t.Fatalf("got %d runs, want 1", len(merged))
+	}
+	if got := merged[0].Run.GetRunAttempt(); got != 2 {
+		t.Errorf("RunAttempt = %d, want 2 (the rerun should win)", got)
+	}
+}
+
+// TestMergeRunsStaleRerunIgnored checks that a previously stored rerun isn't
+// clobbered by an older attempt number seen again (e.g. a delayed webhook).
+func TestMergeRunsStaleRerunIgnored(t *testing.T) {
+	now := time.Now()
+	previous := []*WorkflowRun{newRun(1, 2, now, now)}
+	stale := []*WorkflowRun{newRun(1, 1, now, now.Add(time.Minute))}
+
+	merged := mergeRuns(previous, stale)
+
+	if len(merged) != 1 {
+		t.Fatalf("got %d runs, want 1", len(merged))
+	}
+	if got := merged[0].Run.GetRunAttempt(); got != 2 {
+		t.Errorf("RunAttempt = %d, want 2 (the newer attempt should be kept)", got)
+	}
+}
+
+// TestMergeRunsRetentionTruncation checks that the merged result is capped
+// at maxRunsPerWorkflow and sorted by CreatedAt descending.
+func TestMergeRunsRetentionTruncation(t *testing.T) {
+	orig := maxRunsPerWorkflow
+	maxRunsPerWorkflow = 2
+	defer func() { maxRunsPerWorkflow = orig }()
+
+	now := time.Now()
+	var previous []*WorkflowRun
+	for i := int64(1); i <= 3; i++ {
+		previous = append(previous, newRun(i, 1, now.Add(time.Duration(i)*time.Hour), now))
+	}
+
+	merged := mergeRuns(previous, nil)
+
+	if len(merged) != 2 {
+		t.Fatalf("got %d runs, want 2 (capped at maxRunsPerWorkflow)", len(merged))
+	}
+	if merged[0].Run.GetID() != 3 || merged[1].Run.GetID() != 2 {
+		t.Errorf("merged IDs = [%d, %d], want [3, 2] (newest first)", merged[0].Run.GetID(), merged[1].Run.GetID())
+	}
+}
+
+// TestMergeDataMissingWorkflowKeptUntilPruneWindow checks that a workflow
+// absent from the new scan (e.g. not returned this pass) survives until it
+// hasn't been refreshed for pruneAfterDays, then is dropped.
+func TestMergeDataMissingWorkflowKeptUntilPruneWindow(t *testing.T) {
+	recent := &WorkflowRunData{WorkflowID: 1, WorkflowName: "recent", LastUpdatedAt: time.Now().Add(-1 * 24 * time.Hour)}
+	stale := &WorkflowRunData{WorkflowID: 2, WorkflowName: "stale", LastUpdatedAt: time.Now().Add(-40 * 24 * time.Hour)}
+	previous := map[int64]*WorkflowRunData{1: recent, 2: stale}
+
+	merged := mergeData(previous, map[int64]*WorkflowRunData{}, 30)
+
+	if _, ok := merged[1]; !ok {
+		t.Errorf("workflow 1 (recently updated) should have been kept")
+	}
+	if _, ok := merged[2]; ok {
+		t.Errorf("workflow 2 (stale past pruneAfterDays) should have been pruned")
+	}
+}
+
+// TestMergeDataNewWorkflowMergesRunHistory checks that a workflow present in
+// both previous and new data has its run histories merged, not replaced.
+func TestMergeDataNewWorkflowMergesRunHistory(t *testing.T) {
+	now := time.Now()
+	previous := map[int64]*WorkflowRunData{
+		1: {WorkflowID: 1, WorkflowName: "build", Runs: []*WorkflowRun{newRun(10, 1, now.Add(-time.Hour), now)}, LastUpdatedAt: now.Add(-time.Hour)},
+	}
+	fresh := map[int64]*WorkflowRunData{
+		1: {WorkflowID: 1, WorkflowName: "build", Runs: []*WorkflowRun{newRun(11, 1, now, now)}, LastUpdatedAt: now},
+	}
+
+	merged := mergeData(previous, fresh, 30)
+
+	runs := merged[1].Runs
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2 (histories should be merged, not replaced)", len(runs))
+	}
+}
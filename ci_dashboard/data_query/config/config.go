@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads the optional CONFIG_FILE describing every repo (or
+// whole org) the collector should gather workflow run data for.
+package config
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target describes either a single repo (Owner/Repo) or, when Org is true,
+// every repo in Owner's org (subject to Exclude/Topics).
+type Target struct {
+	Owner    string   `yaml:"owner"`
+	Repo     string   `yaml:"repo"`
+	Org      bool     `yaml:"org"`
+	Branches []string `yaml:"branches"`
+	Exclude  []string `yaml:"exclude"`
+	Topics   []string `yaml:"topics"`
+}
+
+// Config is the top-level shape of CONFIG_FILE.
+type Config struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// Load reads and parses a Config from path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
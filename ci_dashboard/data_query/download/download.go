@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package download fetches artifacts and logs for a workflow run, opt-in
+// since both can be large and aren't needed just to chart run health.
+package download
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v52/github"
+
+	"testingdashboard/m/v2/model"
+)
+
+// Log collection modes for Options.CollectLogs.
+const (
+	CollectLogsNever     = "never"
+	CollectLogsOnFailure = "on_failure"
+	CollectLogsAlways    = "always"
+)
+
+// Options controls what Run fetches for a single workflow run.
+type Options struct {
+	CollectArtifacts bool
+	CollectLogs      string // one of the CollectLogs* constants
+	MaxBytesPerRun   int64  // 0 means unbounded
+}
+
+// Run downloads artifacts (if enabled) into dir/artifacts/<name>/ and job
+// logs (per CollectLogs) into dir/logs/<jobName>.txt, for a single run. It
+// returns artifact metadata to be stored alongside the run.
+func Run(ctx context.Context, client *github.Client, owner, repo string, run *model.WorkflowRun, dir string, opts Options) ([]model.ArtifactMeta, error) {
+	// DownloadArtifact/GetWorkflowJobLogs are called with followRedirects=false
+	// precisely so we fetch the redirect ourselves, with a plain unauthenticated
+	// client: the Location they return points at third-party blob storage, and
+	// sending our GitHub token/App installation token along with that request
+	// would leak it to that host.
+	httpClient := &http.Client{}
+	var (
+		artifacts []model.ArtifactMeta
+		budget    = opts.MaxBytesPerRun
+	)
+
+	if opts.CollectArtifacts {
+		list, err := listRunArtifacts(ctx, client, owner, repo, run.Run.GetID())
+		if err != nil {
+			return artifacts, fmt.Errorf("listing artifacts: %w", err)
+		}
+
+		artifactsDir := filepath.Join(dir, "artifacts")
+		for _, artifact := range list {
+			if opts.MaxBytesPerRun > 0 && (budget <= 0 || artifact.GetSizeInBytes() > budget) {
+				continue
+			}
+
+			artifactURL, _, err := client.Actions.DownloadArtifact(ctx, owner, repo, artifact.GetID(), false)
+			if err != nil {
+				return artifacts, fmt.Errorf("resolving download URL for artifact %s: %w", artifact.GetName(), err)
+			}
+
+			meta, err := fetchZip(ctx, httpClient, artifactURL.String(), artifactsDir, artifact.GetName(), budget)
+			if err != nil {
+				return artifacts, fmt.Errorf("downloading artifact %s: %w", artifact.GetName(), err)
+			}
+			meta.ExpiresAt = artifact.GetExpiresAt().Time
+			artifacts = append(artifacts, meta)
+			if budget > 0 {
+				budget -= meta.SizeBytes
+			}
+		}
+	}
+
+	if shouldCollectLogs(opts.CollectLogs, run.Run.GetConclusion()) {
+		logsDir := filepath.Join(dir, "logs")
+		for jobName, job := range run.Jobs {
+			logURL, _, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, job.GetID(), false)
+			if err != nil {
+				return artifacts, fmt.Errorf("resolving log URL for job %s: %w", jobName, err)
+			}
+			if err := fetchText(ctx, httpClient, logURL.String(), filepath.Join(logsDir, jobName+".txt"), opts.MaxBytesPerRun); err != nil {
+				return artifacts, fmt.Errorf("downloading logs for job %s: %w", jobName, err)
+			}
+		}
+	}
+
+	return artifacts, nil
+}
+
+// listRunArtifacts lists every artifact attached to runID, paginating past
+// GitHub's per-page cap so runs with more than 100 artifacts aren't silently
+// truncated.
+func listRunArtifacts(ctx context.Context, client *github.Client, owner, repo string, runID int64) ([]*github.Artifact, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var artifacts []*github.Artifact
+	for {
+		list, resp, err := client.Actions.ListWorkflowRunArtifacts(ctx, owner, repo, runID, opts)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, list.Artifacts...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return artifacts, nil
+}
+
+// shouldCollectLogs applies the CollectLogs policy to a run's conclusion.
+func shouldCollectLogs(mode, conclusion string) bool {
+	switch mode {
+	case CollectLogsAlways:
+		return true
+	case CollectLogsOnFailure:
+		return conclusion == "failure"
+	default:
+		return false
+	}
+}
+
+// fetchZip downloads url, verifies it's a zip, and unpacks it under
+// destDir/name/. maxBytes of 0 means unbounded.
+func fetchZip(ctx context.Context, httpClient *http.Client, url, destDir, name string, maxBytes int64) (model.ArtifactMeta, error) {
+	body, sha, err := get(ctx, httpClient, url, maxBytes)
+	if err != nil {
+		return model.ArtifactMeta{}, err
+	}
+	if !looksLikeZip(body) {
+		return model.ArtifactMeta{}, fmt.Errorf("expected a zip response, got %s", http.DetectContentType(body))
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return model.ArtifactMeta{}, err
+	}
+
+	outDir := filepath.Join(destDir, name)
+	for _, f := range zr.File {
+		if err := extractZipFile(f, outDir, maxBytes); err != nil {
+			return model.ArtifactMeta{}, err
+		}
+	}
+
+	return model.ArtifactMeta{Name: name, SizeBytes: int64(len(body)), SHA256: sha}, nil
+}
+
+// fetchText downloads url, verifies it's not a zip, and writes it to path.
+func fetchText(ctx context.Context, httpClient *http.Client, url, path string, maxBytes int64) error {
+	body, _, err := get(ctx, httpClient, url, maxBytes)
+	if err != nil {
+		return err
+	}
+	if looksLikeZip(body) {
+		return fmt.Errorf("expected a plain-text log, got a zip response")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0644)
+}
+
+// get issues a GET through httpClient, enforcing maxBytes (0 means
+// unbounded) and returning the body alongside its SHA-256. httpClient is
+// deliberately not the authenticated GitHub API client: callers pass it the
+// pre-signed artifact/log redirect URL, which must be fetched without our
+// credentials attached.
+func get(ctx context.Context, httpClient *http.Client, url string, maxBytes int64) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	reader := io.Reader(resp.Body)
+	if maxBytes > 0 {
+		reader = io.LimitReader(resp.Body, maxBytes+1)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", err
+	}
+	if maxBytes > 0 && int64(len(body)) > maxBytes {
+		return nil, "", fmt.Errorf("response exceeds %d byte cap", maxBytes)
+	}
+
+	sum := sha256.Sum256(body)
+	return body, hex.EncodeToString(sum[:]), nil
+}
+
+// looksLikeZip checks for the local-file-header magic bytes zip archives
+// start with, since GitHub's artifact endpoint doesn't always set a
+// reliable Content-Type.
+func looksLikeZip(body []byte) bool {
+	return len(body) >= 4 && body[0] == 'P' && body[1] == 'K' && body[2] == 3 && body[3] == 4
+}
+
+// extractZipFile writes a single zip entry under destDir, guarding against
+// zip-slip by rejecting entries that would escape it. maxBytes (0 means
+// unbounded) caps the decompressed size written to disk, independent of the
+// compressed size budget enforced when the zip itself was downloaded: a
+// crafted or anomalous entry can decompress to far more bytes than it took
+// over the wire.
+func extractZipFile(f *zip.File, destDir string, maxBytes int64) error {
+	path := filepath.Join(destDir, f.Name)
+	// A plain strings.HasPrefix(path, destDir) check is bypassable: an entry
+	// named "../<destDir-basename>-evil/pwned.txt" cleans to a path that still
+	// has destDir as a string prefix despite escaping it. Requiring the
+	// separator rules that out.
+	if !strings.HasPrefix(path, destDir+string(os.PathSeparator)) {
+		return fmt.Errorf("illegal file path in artifact: %s", f.Name)
+	}
+
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(path, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	reader := io.Reader(rc)
+	if maxBytes > 0 {
+		reader = io.LimitReader(rc, maxBytes+1)
+	}
+
+	written, err := io.Copy(out, reader)
+	if err != nil {
+		return err
+	}
+	if maxBytes > 0 && written > maxBytes {
+		return fmt.Errorf("decompressed entry %s exceeds %d byte cap", f.Name, maxBytes)
+	}
+	return nil
+}
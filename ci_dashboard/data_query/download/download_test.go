@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// zipWithEntry builds an in-memory zip archive containing a single entry
+// named name with the given contents, and returns the *zip.File for it.
+func zipWithEntry(t *testing.T, name, contents string) *zip.File {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	if err != nil {
+		t.Fatalf("creating zip entry: %v", err)
+	}
+	if _, err := w.Write([]byte(contents)); err != nil {
+		t.Fatalf("writing zip entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading back zip: %v", err)
+	}
+	return zr.File[0]
+}
+
+func TestExtractZipFileRejectsZipSlip(t *testing.T) {
+	destDir := t.TempDir()
+	f := zipWithEntry(t, "../"+filepath.Base(destDir)+"-evil/pwned.txt", "pwned")
+
+	if err := extractZipFile(f, destDir, 0); err == nil {
+		t.Fatal("expected an error for a path-escaping zip entry, got nil")
+	}
+
+	escaped := filepath.Join(filepath.Dir(destDir), filepath.Base(destDir)+"-evil", "pwned.txt")
+	if _, err := os.Stat(escaped); !os.IsNotExist(err) {
+		t.Fatalf("extractZipFile wrote outside destDir: %s", escaped)
+	}
+}
+
+func TestExtractZipFileWritesWithinDestDir(t *testing.T) {
+	destDir := t.TempDir()
+	f := zipWithEntry(t, "artifact.txt", "hello")
+
+	if err := extractZipFile(f, destDir, 0); err != nil {
+		t.Fatalf("extractZipFile: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(destDir, "artifact.txt"))
+	if err != nil {
+		t.Fatalf("reading extracted file: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("got %q, want %q", got, "hello")
+	}
+}
+
+func TestExtractZipFileEnforcesDecompressedCap(t *testing.T) {
+	destDir := t.TempDir()
+	f := zipWithEntry(t, "artifact.txt", "hello world")
+
+	if err := extractZipFile(f, destDir, 5); err == nil {
+		t.Fatal("expected an error for an entry exceeding the decompressed byte cap, got nil")
+	}
+}
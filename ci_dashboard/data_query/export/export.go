@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export turns collected workflow run data into the file formats
+// downstream tools consume: the original JSON dump, JUnit XML for CI
+// dashboards, and Prometheus text exposition for monitoring.
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/go-github/v52/github"
+
+	"testingdashboard/m/v2/model"
+)
+
+// DefaultFormat is used when EXPORT_FORMATS isn't set, preserving the
+// collector's original JSON-only behavior.
+const DefaultFormat = "json"
+
+// Exporter writes collected workflow run data out in some format.
+type Exporter interface {
+	Write(data map[int64]*model.WorkflowRunData) error
+}
+
+// Resolve returns one Exporter per requested format, in order, each writing
+// under dir using the format's default filename. It returns an error if any
+// format is unknown.
+func Resolve(formats []string, dir string) ([]Exporter, error) {
+	exporters := make([]Exporter, 0, len(formats))
+	for _, format := range formats {
+		switch format {
+		case "json":
+			exporters = append(exporters, &JSONExporter{Path: filepath.Join(dir, "workflow_runs.json")})
+		case "junit":
+			exporters = append(exporters, &JUnitExporter{Path: filepath.Join(dir, "junit.xml")})
+		case "prometheus":
+			exporters = append(exporters, &PrometheusExporter{Path: filepath.Join(dir, "workflow_runs.prom")})
+		default:
+			return nil, fmt.Errorf("unknown export format %q", format)
+		}
+	}
+	return exporters, nil
+}
+
+// sortedWorkflows returns data's values sorted by WorkflowID, so exporters
+// that range over it (unlike the JSON exporter, which inherits
+// encoding/json's own deterministic key ordering) produce stable,
+// diffable output across runs.
+func sortedWorkflows(data map[int64]*model.WorkflowRunData) []*model.WorkflowRunData {
+	workflows := make([]*model.WorkflowRunData, 0, len(data))
+	for _, workflow := range data {
+		workflows = append(workflows, workflow)
+	}
+	sort.Slice(workflows, func(i, j int) bool {
+		return workflows[i].WorkflowID < workflows[j].WorkflowID
+	})
+	return workflows
+}
+
+// sortedJobNames returns jobs' keys sorted, for the same reason
+// sortedWorkflows exists.
+func sortedJobNames(jobs map[string]*github.WorkflowJob) []string {
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"time"
+
+	"github.com/google/go-github/v52/github"
+	"testingdashboard/m/v2/model"
+)
+
+// sampleData returns two workflows, each with a single run, one of which has
+// two jobs. Both workflow and job maps are deliberately multi-entry so the
+// JUnit and Prometheus exporters' sort-before-emit logic is actually
+// exercised: the raw map iteration order would otherwise be free to vary
+// between runs and still pass a golden-file comparison by accident.
+func sampleData() map[int64]*model.WorkflowRunData {
+	runStart := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	runEnd := runStart.Add(5 * time.Minute)
+	jobEnd := runStart.Add(2 * time.Minute)
+
+	return map[int64]*model.WorkflowRunData{
+		42: {
+			WorkflowID:   42,
+			WorkflowName: "build",
+			WorkflowURL:  "https://github.com/o/r/actions/workflows/build.yml",
+			Runs: []*model.WorkflowRun{
+				{
+					Run: &github.WorkflowRun{
+						ID:         github.Int64(100),
+						Conclusion: github.String("failure"),
+						CreatedAt:  &github.Timestamp{Time: runStart},
+						UpdatedAt:  &github.Timestamp{Time: runEnd},
+					},
+					Jobs: map[string]*github.WorkflowJob{
+						"test": {
+							Conclusion:  github.String("failure"),
+							StartedAt:   &github.Timestamp{Time: runStart},
+							CompletedAt: &github.Timestamp{Time: jobEnd},
+						},
+						"build": {
+							Conclusion:  github.String("success"),
+							StartedAt:   &github.Timestamp{Time: runStart},
+							CompletedAt: &github.Timestamp{Time: jobEnd},
+						},
+					},
+				},
+			},
+			LastUpdatedAt: runEnd,
+		},
+		7: {
+			WorkflowID:   7,
+			WorkflowName: "lint",
+			WorkflowURL:  "https://github.com/o/r/actions/workflows/lint.yml",
+			Runs: []*model.WorkflowRun{
+				{
+					Run: &github.WorkflowRun{
+						ID:         github.Int64(50),
+						Conclusion: github.String("success"),
+						CreatedAt:  &github.Timestamp{Time: runStart},
+						UpdatedAt:  &github.Timestamp{Time: runEnd},
+					},
+					Jobs: map[string]*github.WorkflowJob{
+						"lint": {
+							Conclusion:  github.String("success"),
+							StartedAt:   &github.Timestamp{Time: runStart},
+							CompletedAt: &github.Timestamp{Time: jobEnd},
+						},
+					},
+				},
+			},
+			LastUpdatedAt: runEnd,
+		},
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"os"
+
+	"testingdashboard/m/v2/model"
+)
+
+// JSONExporter writes the collected data as a single indented JSON file,
+// the collector's original (and still default) output format.
+type JSONExporter struct {
+	Path string
+}
+
+// Write implements Exporter.
+func (e *JSONExporter) Write(data map[int64]*model.WorkflowRunData) error {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(e.Path, jsonData, 0644)
+}
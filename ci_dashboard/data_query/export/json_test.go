@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v52/github"
+	"testingdashboard/m/v2/model"
+)
+
+// TestJSONExporterWrite checks the exact bytes written against
+// testdata/json.golden. The fixture leaves Run nil and Jobs empty so the
+// expected output doesn't depend on go-github's own JSON tags, only on
+// model.WorkflowRunData's.
+func TestJSONExporterWrite(t *testing.T) {
+	data := map[int64]*model.WorkflowRunData{
+		42: {
+			WorkflowID:   42,
+			WorkflowName: "build",
+			WorkflowURL:  "https://github.com/o/r/actions/workflows/build.yml",
+			Runs: []*model.WorkflowRun{
+				{Run: nil, Jobs: map[string]*github.WorkflowJob{}},
+			},
+			LastUpdatedAt: time.Date(2026, 1, 1, 0, 5, 0, 0, time.UTC),
+		},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "workflow_runs.json")
+	exporter := &JSONExporter{Path: path}
+	if err := exporter.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	want, err := os.ReadFile("testdata/json.golden")
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("output does not match testdata/json.golden\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+
+	"testingdashboard/m/v2/model"
+)
+
+// JUnitExporter writes one <testsuite> per workflow and one <testcase> per
+// job, so CI dashboards that already understand JUnit can chart workflow
+// health without a custom integration.
+type JUnitExporter struct {
+	Path string
+}
+
+type junitTestsuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestsuite `xml:"testsuite"`
+}
+
+type junitTestsuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Testcases []junitTestcase `xml:"testcase"`
+}
+
+type junitTestcase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// Write implements Exporter.
+func (e *JUnitExporter) Write(data map[int64]*model.WorkflowRunData) error {
+	out := junitTestsuites{Suites: make([]junitTestsuite, 0, len(data))}
+
+	for _, workflow := range sortedWorkflows(data) {
+		suite := junitTestsuite{Name: workflow.WorkflowName}
+		for _, run := range workflow.Runs {
+			for _, jobName := range sortedJobNames(run.Jobs) {
+				job := run.Jobs[jobName]
+				tc := junitTestcase{
+					Name:      jobName,
+					Classname: workflow.WorkflowName,
+					Time:      job.GetCompletedAt().Sub(job.GetStartedAt().Time).Seconds(),
+				}
+				if job.GetConclusion() == "failure" {
+					tc.Failure = &junitFailure{
+						Message: fmt.Sprintf("job %q concluded failure in run %d", jobName, run.Run.GetID()),
+					}
+					suite.Failures++
+				}
+				suite.Tests++
+				suite.Testcases = append(suite.Testcases, tc)
+			}
+		}
+		out.Suites = append(out.Suites, suite)
+	}
+
+	body, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	body = append([]byte(xml.Header), body...)
+	return os.WriteFile(e.Path, body, 0644)
+}
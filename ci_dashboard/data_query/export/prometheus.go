@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"testingdashboard/m/v2/model"
+)
+
+// PrometheusExporter writes the collected data as Prometheus text exposition
+// gauges, for scraping by a node_exporter textfile collector or similar.
+type PrometheusExporter struct {
+	Path string
+}
+
+// Write implements Exporter.
+func (e *PrometheusExporter) Write(data map[int64]*model.WorkflowRunData) error {
+	var b strings.Builder
+
+	b.WriteString("# HELP github_workflow_run_duration_seconds Duration of the most recent workflow runs.\n")
+	b.WriteString("# TYPE github_workflow_run_duration_seconds gauge\n")
+	workflows := sortedWorkflows(data)
+	for _, workflow := range workflows {
+		for _, run := range workflow.Runs {
+			duration := run.Run.GetUpdatedAt().Sub(run.Run.GetCreatedAt().Time).Seconds()
+			fmt.Fprintf(&b, "github_workflow_run_duration_seconds{workflow=%q,run_id=%q} %g\n",
+				workflow.WorkflowName, strconv.FormatInt(run.Run.GetID(), 10), duration)
+		}
+	}
+
+	b.WriteString("# HELP github_workflow_run_conclusion Most recent workflow runs by conclusion.\n")
+	b.WriteString("# TYPE github_workflow_run_conclusion gauge\n")
+	for _, workflow := range workflows {
+		for _, run := range workflow.Runs {
+			fmt.Fprintf(&b, "github_workflow_run_conclusion{workflow=%q,conclusion=%q,run_id=%q} 1\n",
+				workflow.WorkflowName, run.Run.GetConclusion(), strconv.FormatInt(run.Run.GetID(), 10))
+		}
+	}
+
+	b.WriteString("# HELP github_workflow_job_duration_seconds Duration of jobs within the most recent workflow runs.\n")
+	b.WriteString("# TYPE github_workflow_job_duration_seconds gauge\n")
+	for _, workflow := range workflows {
+		for _, run := range workflow.Runs {
+			for _, jobName := range sortedJobNames(run.Jobs) {
+				job := run.Jobs[jobName]
+				duration := job.GetCompletedAt().Sub(job.GetStartedAt().Time).Seconds()
+				fmt.Fprintf(&b, "github_workflow_job_duration_seconds{workflow=%q,job=%q,run_id=%q} %g\n",
+					workflow.WorkflowName, jobName, strconv.FormatInt(run.Run.GetID(), 10), duration)
+			}
+		}
+	}
+
+	return os.WriteFile(e.Path, []byte(b.String()), 0644)
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPrometheusExporterWrite checks the exact bytes written against
+// testdata/prometheus.golden, covering the run-duration, run-conclusion,
+// and job-duration gauge families.
+func TestPrometheusExporterWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "workflow_runs.prom")
+	exporter := &PrometheusExporter{Path: path}
+	if err := exporter.Write(sampleData()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	want, err := os.ReadFile("testdata/prometheus.golden")
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("output does not match testdata/prometheus.golden\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
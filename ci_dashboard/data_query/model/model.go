@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package model holds the collected workflow run data shared between the
+// collector and its exporters, so exporters don't need to import package
+// collector.
+package model
+
+import (
+	"time"
+
+	"github.com/google/go-github/v52/github"
+)
+
+// WorkflowRun pairs a single GitHub Actions run with its jobs, keyed by job
+// name.
+type WorkflowRun struct {
+	Run       *github.WorkflowRun            `json:"run"`
+	Jobs      map[string]*github.WorkflowJob `json:"jobs"`
+	Artifacts []ArtifactMeta                 `json:"artifacts,omitempty"`
+}
+
+// ArtifactMeta records where a downloaded build artifact ended up on disk,
+// so downstream tools can find it without re-hitting the GitHub API.
+type ArtifactMeta struct {
+	Name      string    `json:"name"`
+	SizeBytes int64     `json:"size_bytes"`
+	SHA256    string    `json:"sha256"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// WorkflowRunData represents the combined data for a workflow.
+type WorkflowRunData struct {
+	WorkflowID    int64          `json:"workflow_id"`
+	WorkflowName  string         `json:"workflow_name"`
+	WorkflowURL   string         `json:"workflow_url"`
+	Runs          []*WorkflowRun `json:"runs"`
+	LastUpdatedAt time.Time      `json:"last_updated_at"`
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit provides a client-side governor that adapts request
+// concurrency to GitHub's rate limit headers and backs off on 403/429
+// responses, so a single collector run doesn't trip abuse detection.
+package ratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Governor bounds request concurrency and tracks GitHub's advertised rate
+// limit budget. It is safe for concurrent use.
+type Governor struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	current int
+	limit   int
+	minimum int
+	maximum int
+
+	threshold int
+	remaining int
+	resetAt   time.Time
+
+	RequestCount int64
+	RetryCount   int64
+	LongestWait  time.Duration
+}
+
+// NewGovernor creates a Governor that allows up to maxConcurrency in-flight
+// requests, shrinking toward 1 as the remaining quota approaches threshold.
+func NewGovernor(maxConcurrency, threshold int) *Governor {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	g := &Governor{
+		current:   0,
+		limit:     maxConcurrency,
+		minimum:   1,
+		maximum:   maxConcurrency,
+		threshold: threshold,
+		remaining: maxConcurrency, // optimistic until we see a real header
+	}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+// Acquire blocks until a concurrency slot is free and, while the remaining
+// quota is below threshold, until the reset window has passed.
+func (g *Governor) Acquire() {
+	g.mu.Lock()
+	for {
+		if g.remaining < g.threshold && time.Now().Before(g.resetAt) {
+			wait := time.Until(g.resetAt)
+			g.mu.Unlock()
+			g.recordWait(wait)
+			time.Sleep(wait)
+			g.mu.Lock()
+			continue
+		}
+		if g.current < g.limit {
+			break
+		}
+		g.cond.Wait()
+	}
+	g.current++
+	g.mu.Unlock()
+}
+
+// Release frees a concurrency slot acquired via Acquire.
+func (g *Governor) Release() {
+	g.mu.Lock()
+	g.current--
+	g.cond.Signal()
+	g.mu.Unlock()
+}
+
+// Observe updates the governor's view of the remaining quota from resp's
+// rate limit headers and grows or shrinks the concurrency limit to match.
+func (g *Governor) Observe(resp *http.Response) {
+	remaining, ok := parseInt(resp.Header.Get("X-RateLimit-Remaining"))
+	if !ok {
+		return
+	}
+	resetAt := parseEpoch(resp.Header.Get("X-RateLimit-Reset"))
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remaining = remaining
+	if !resetAt.IsZero() {
+		g.resetAt = resetAt
+	}
+
+	switch {
+	case remaining < g.threshold:
+		g.limit = g.minimum
+	case remaining < g.threshold*4:
+		if g.limit > g.minimum {
+			g.limit--
+		}
+	default:
+		if g.limit < g.maximum {
+			g.limit++
+		}
+	}
+	g.cond.Broadcast()
+}
+
+// Remaining returns the most recently observed rate limit budget.
+func (g *Governor) Remaining() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.remaining
+}
+
+func (g *Governor) recordWait(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if d > g.LongestWait {
+		g.LongestWait = d
+	}
+}
+
+func parseInt(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+func parseEpoch(s string) time.Time {
+	v, ok := parseInt(s)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(int64(v), 0)
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     https://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	baseBackoff = 1 * time.Second
+	maxBackoff  = 5 * time.Minute
+	maxAttempts = 6
+)
+
+// Transport wraps another http.RoundTripper, gating requests through a
+// Governor and retrying 403/429 responses with jittered exponential backoff,
+// honoring Retry-After precisely when GitHub sends one.
+type Transport struct {
+	Base     http.RoundTripper
+	Governor *Governor
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	g := t.Governor
+	backoff := baseBackoff
+
+	// attempt counts requests already made, starting at 1 for the first one,
+	// so the attempt >= maxAttempts check below caps the total at exactly
+	// maxAttempts requests rather than allowing one extra.
+	for attempt := 1; ; attempt++ {
+		g.Acquire()
+		atomic.AddInt64(&g.RequestCount, 1)
+		resp, err := t.base().RoundTrip(req)
+		g.Release()
+		if err != nil {
+			return nil, err
+		}
+		g.Observe(resp)
+
+		if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		if attempt >= maxAttempts {
+			return resp, nil
+		}
+
+		wait := retryAfter(resp)
+		if wait == 0 {
+			wait = backoff + jitter(backoff)
+			if backoff < maxBackoff {
+				backoff *= 2
+				if backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+			}
+		}
+		g.recordWait(wait)
+		resp.Body.Close()
+		atomic.AddInt64(&g.RetryCount, 1)
+		time.Sleep(wait)
+	}
+}
+
+// retryAfter returns how long to wait before retrying, honoring a
+// server-supplied Retry-After header precisely. It returns 0 when the
+// response carries no such hint, leaving the caller to fall back to backoff.
+func retryAfter(resp *http.Response) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 0
+}
+
+// jitter returns a random duration in [0, d/2), smoothing out retry storms
+// from multiple goroutines backing off in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) / 2))
+}